@@ -0,0 +1,95 @@
+package appconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// removeSentinelKey, when present inside a map regardless of its value,
+// deletes the containing key from the merge result entirely instead of
+// recursing into it. It lets a later layer remove something an earlier
+// layer set (e.g. a whole http_service block) rather than only ever being
+// able to replace it.
+const removeSentinelKey = "__remove__"
+
+// LoadConfigLayers loads each path (mixing TOML, JSON and YAML is allowed),
+// normalizes every layer to a map[string]any the same way LoadConfig does,
+// and deep-merges them left to right: scalars and arrays from later layers
+// replace earlier ones, maps recurse, and a "__remove__": true sentinel in a
+// later layer deletes the containing key. applyPatches runs once, on the
+// fully merged map, so layer-specific migrations never fight each other.
+func LoadConfigLayers(paths []string) (cfg *Config, err error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no config layers given")
+	}
+
+	merged := map[string]any{}
+	for _, path := range paths {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		layer, err := decodeConfigMap(path, buf)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		merged = mergeConfigMaps(merged, layer)
+	}
+
+	cfg, err = applyPatches(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.configFilePath = paths[len(paths)-1]
+	return cfg, nil
+}
+
+// decodeConfigMap parses buf into a generic map according to path's
+// extension, reusing the same per-format decoders and the same suffix
+// dispatch LoadConfig uses, but without running applyPatches: layered
+// loading only applies patches once, after merging.
+func decodeConfigMap(path string, buf []byte) (map[string]any, error) {
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		return decodeJSONMap(buf)
+	case strings.HasSuffix(path, ".yaml"):
+		return decodeYAMLMap(buf)
+	default:
+		return decodeTOMLMap(buf)
+	}
+}
+
+// mergeConfigMaps deep-merges src into dst in place and returns dst. The
+// sentinel key itself is always stripped, whatever its value, so it never
+// leaks into the merged result.
+func mergeConfigMaps(dst, src map[string]any) map[string]any {
+	for k, v := range src {
+		if k == removeSentinelKey {
+			continue
+		}
+
+		srcMap, srcIsMap := v.(map[string]any)
+		if srcIsMap {
+			if _, ok := srcMap[removeSentinelKey]; ok {
+				delete(dst, k)
+				continue
+			}
+
+			dstMap, ok := dst[k].(map[string]any)
+			if !ok {
+				dstMap = map[string]any{}
+			}
+			dst[k] = mergeConfigMaps(dstMap, srcMap)
+			continue
+		}
+
+		dst[k] = v
+	}
+
+	return dst
+}
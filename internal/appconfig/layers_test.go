@@ -0,0 +1,117 @@
+package appconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLayer(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
+
+func TestMergeConfigMapsScalarAndArrayReplace(t *testing.T) {
+	dst := map[string]any{
+		"app":     "base",
+		"regions": []any{"iad", "lhr"},
+	}
+	src := map[string]any{
+		"app":     "override",
+		"regions": []any{"syd"},
+	}
+
+	got := mergeConfigMaps(dst, src)
+
+	assert.Equal(t, "override", got["app"])
+	assert.Equal(t, []any{"syd"}, got["regions"])
+}
+
+func TestMergeConfigMapsRecursesIntoMaps(t *testing.T) {
+	dst := map[string]any{"env": map[string]any{"A": "1", "B": "2"}}
+	src := map[string]any{"env": map[string]any{"B": "3", "C": "4"}}
+
+	got := mergeConfigMaps(dst, src)
+
+	assert.Equal(t, map[string]any{"A": "1", "B": "3", "C": "4"}, got["env"])
+}
+
+func TestMergeConfigMapsRemoveSentinelDeletesKey(t *testing.T) {
+	dst := map[string]any{"http_service": map[string]any{"port": 8080}}
+	src := map[string]any{"http_service": map[string]any{"__remove__": true}}
+
+	got := mergeConfigMaps(dst, src)
+
+	_, ok := got["http_service"]
+	assert.False(t, ok)
+}
+
+func TestMergeConfigMapsRemoveSentinelIsScopedToContainingKey(t *testing.T) {
+	dst := map[string]any{
+		"http_service": map[string]any{
+			"port":   8080,
+			"checks": map[string]any{"path": "/"},
+		},
+	}
+	src := map[string]any{
+		"http_service": map[string]any{
+			"checks": map[string]any{"__remove__": true},
+		},
+	}
+
+	got := mergeConfigMaps(dst, src)
+
+	httpService := got["http_service"].(map[string]any)
+	_, ok := httpService["checks"]
+	assert.False(t, ok)
+	assert.Equal(t, 8080, httpService["port"])
+}
+
+func TestDecodeConfigMapMixesFormats(t *testing.T) {
+	tomlLayer, err := decodeConfigMap("base.toml", []byte("app = \"base\"\nprimary_region = \"iad\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "base", tomlLayer["app"])
+
+	yamlLayer, err := decodeConfigMap("prod.overlay.yaml", []byte("app: prod\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", yamlLayer["app"])
+
+	jsonLayer, err := decodeConfigMap("extra.json", []byte(`{"app": "json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "json", jsonLayer["app"])
+}
+
+func TestLoadConfigLayersMergesInDeclaredOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	base := writeLayer(t, dir, "base.toml", `
+app = "my-app"
+primary_region = "iad"
+
+[env]
+LOG_LEVEL = "info"
+`)
+	overlay := writeLayer(t, dir, "prod.overlay.yaml", `
+env:
+  LOG_LEVEL: warn
+  EXTRA: set-in-overlay
+`)
+
+	cfg, err := LoadConfigLayers([]string{base, overlay})
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-app", cfg.AppName)
+	assert.Equal(t, "iad", cfg.PrimaryRegion)
+	assert.Equal(t, "warn", cfg.Env["LOG_LEVEL"])
+	assert.Equal(t, "set-in-overlay", cfg.Env["EXTRA"])
+}
+
+func TestLoadConfigLayersRequiresAtLeastOnePath(t *testing.T) {
+	_, err := LoadConfigLayers(nil)
+	require.Error(t, err)
+}
@@ -153,7 +153,11 @@ func (c *Config) marshalTOML() ([]byte, error) {
 	return b.Bytes(), nil
 }
 
-func unmarshalTOML(buf []byte) (*Config, error) {
+// decodeTOMLMap, decodeJSONMap and decodeYAMLMap decode buf into a generic
+// map without applying patches, so LoadConfig (which patches immediately)
+// and LoadConfigLayers (which patches once, after merging every layer) can
+// share the same per-format decoding.
+func decodeTOMLMap(buf []byte) (map[string]any, error) {
 	cfgMap := map[string]any{}
 	if err := toml.Unmarshal(buf, &cfgMap); err != nil {
 		var derr *toml.DecodeError
@@ -163,6 +167,31 @@ func unmarshalTOML(buf []byte) (*Config, error) {
 		}
 		return nil, err
 	}
+	return cfgMap, nil
+}
+
+func decodeJSONMap(buf []byte) (map[string]any, error) {
+	cfgMap := map[string]any{}
+	if err := json.Unmarshal(buf, &cfgMap); err != nil {
+		return nil, err
+	}
+	return cfgMap, nil
+}
+
+func decodeYAMLMap(buf []byte) (map[string]any, error) {
+	cfgMap := map[string]any{}
+	if err := yaml.Unmarshal(buf, &cfgMap); err != nil {
+		return nil, err
+	}
+	stringifyYAMLMapKeys(cfgMap)
+	return cfgMap, nil
+}
+
+func unmarshalTOML(buf []byte) (*Config, error) {
+	cfgMap, err := decodeTOMLMap(buf)
+	if err != nil {
+		return nil, err
+	}
 	cfg, err := applyPatches(cfgMap)
 
 	// In case of parsing error fallback to bare compatibility
@@ -182,8 +211,8 @@ func unmarshalTOML(buf []byte) (*Config, error) {
 }
 
 func unmarshalJSON(buf []byte) (*Config, error) {
-	cfgMap := map[string]any{}
-	if err := json.Unmarshal(buf, &cfgMap); err != nil {
+	cfgMap, err := decodeJSONMap(buf)
+	if err != nil {
 		return nil, err
 	}
 	cfg, err := applyPatches(cfgMap)
@@ -205,11 +234,10 @@ func unmarshalJSON(buf []byte) (*Config, error) {
 }
 
 func unmarshalYAML(buf []byte) (*Config, error) {
-	cfgMap := map[string]any{}
-	if err := yaml.Unmarshal(buf, &cfgMap); err != nil {
+	cfgMap, err := decodeYAMLMap(buf)
+	if err != nil {
 		return nil, err
 	}
-	stringifyYAMLMapKeys(cfgMap)
 	cfg, err := applyPatches(cfgMap)
 
 	// In case of parsing error fallback to bare compatibility
@@ -0,0 +1,37 @@
+package deploy
+
+import (
+	"context"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+const configLayersFlagName = "config"
+
+// configLayersFlag is the repeatable -c/--config flag used to layer several
+// config files into one: fly deploy -c base.toml -c prod.overlay.yaml merges
+// prod.overlay.yaml on top of base.toml via appconfig.LoadConfigLayers. A
+// single -c behaves the same as the existing --config flag.
+var configLayersFlag = flag.StringSlice{
+	Name:        configLayersFlagName,
+	Shorthand:   "c",
+	Description: "Path to an app configuration file. Repeat to layer several files, later ones overlaying earlier ones",
+}
+
+// configFromLayersFlag loads the app config from --config when it was passed
+// more than once, merging every layer via appconfig.LoadConfigLayers. It
+// returns false, nil, nil when --config was passed zero or one times so
+// callers fall back to the existing single-file config resolution.
+func configFromLayersFlag(ctx context.Context) (bool, *appconfig.Config, error) {
+	paths := flag.GetStringSlice(ctx, configLayersFlagName)
+	if len(paths) < 2 {
+		return false, nil, nil
+	}
+
+	cfg, err := appconfig.LoadConfigLayers(paths)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, cfg, nil
+}
@@ -0,0 +1,53 @@
+package deploy
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+// New returns the `fly deploy` command.
+func New() *cobra.Command {
+	const (
+		usage = "deploy"
+		short = "Deploy an app"
+		long  = "Deploy an app from source. Pass --manifest to deploy verbatim from a\n" +
+			"manifest produced by --export-manifest, skipping local flag/config\n" +
+			"resolution entirely."
+	)
+
+	cmd := command.New(usage, short, long, run,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		manifestFlag,
+		signKeyFlag,
+		trustedKeysDirFlag,
+		requireSignedManifestFlag,
+		configLayersFlag,
+	)
+
+	return cmd
+}
+
+func run(ctx context.Context) error {
+	if handled, err := deployFromManifestFlag(ctx); handled {
+		return err
+	}
+
+	if layered, cfg, err := configFromLayersFlag(ctx); err != nil {
+		return err
+	} else if layered {
+		ctx = appconfig.WithConfig(ctx, cfg)
+	}
+
+	return runMachineDeployment(ctx, appconfig.NameFromContext(ctx), MachineDeploymentArgs{}, nil)
+}
@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newFollow() *cobra.Command {
+	const (
+		usage = "follow"
+		short = "Follow a deploy's progress from `fly deploy serve-events`"
+		long  = "Connect to a `fly deploy serve-events` endpoint and print progress\n" +
+			"events as they arrive, until the deploy reports Done."
+	)
+	cmd := command.New(usage, short, long, runFollow)
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(
+		cmd,
+		flag.String{
+			Name:        "url",
+			Description: "URL of a `fly deploy serve-events` endpoint",
+		},
+	)
+
+	return cmd
+}
+
+func runFollow(ctx context.Context) error {
+	url := flag.GetString(ctx, "url")
+	if url == "" {
+		return errors.New("--url is required")
+	}
+
+	ios := iostreams.FromContext(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	defer resp.Body.Close() // skipcq: GO-S2307
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to connect to %s (status code %d)", url, resp.StatusCode)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var event FollowerEvent
+		if err := dec.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("follower connection closed before a done event arrived")
+			}
+			return err
+		}
+
+		printFollowerEvent(ios, event)
+
+		if event.Type == EventDone {
+			if event.Err != "" {
+				return fmt.Errorf("deploy failed: %s", event.Err)
+			}
+			return nil
+		}
+	}
+}
+
+func printFollowerEvent(streams *iostreams.IOStreams, event FollowerEvent) {
+	switch event.Type {
+	case EventReleaseCommandStarted:
+		fmt.Fprintln(streams.Out, "Release command started")
+	case EventMachineUpdated:
+		fmt.Fprintf(streams.Out, "Machine %s: %s -> %s\n", event.MachineID, event.FromState, event.ToState)
+	case EventHealthCheckResult:
+		status := "passed"
+		if !event.HealthCheckPassed {
+			status = "failed"
+		}
+		fmt.Fprintf(streams.Out, "Machine %s: health check %s %s\n", event.MachineID, event.HealthCheckName, status)
+	case EventDone:
+		if event.Err != "" {
+			fmt.Fprintf(streams.Out, "Deploy failed: %s\n", event.Err)
+		} else {
+			fmt.Fprintln(streams.Out, "Deploy complete")
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBroadcasterDeliversToAllFollowers(t *testing.T) {
+	b := NewEventBroadcaster()
+
+	a := b.AddFollower("a")
+	c := b.AddFollower("c")
+
+	b.Publish(MachineUpdatedEvent("1234567890abcd", "starting", "started"))
+
+	select {
+	case event := <-a:
+		assert.Equal(t, EventMachineUpdated, event.Type)
+	case <-time.After(time.Second):
+		t.Fatal("follower a did not receive event")
+	}
+
+	select {
+	case event := <-c:
+		assert.Equal(t, EventMachineUpdated, event.Type)
+	case <-time.After(time.Second):
+		t.Fatal("follower c did not receive event")
+	}
+}
+
+func TestEventBroadcasterRemoveFollowerClosesChannel(t *testing.T) {
+	b := NewEventBroadcaster()
+	events := b.AddFollower("a")
+
+	b.RemoveFollower("a")
+
+	_, ok := <-events
+	assert.False(t, ok, "expected channel to be closed after RemoveFollower")
+}
+
+func TestEventBroadcasterDropsSlowFollowerWithoutBlocking(t *testing.T) {
+	b := NewEventBroadcaster()
+	slow := b.AddFollower("slow")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < followerChanBuffer+10; i++ {
+			b.Publish(DoneEvent(nil))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Publish blocked on a slow follower instead of dropping it")
+	}
+
+	// The slow follower's channel should have been closed once its buffer
+	// filled, rather than Publish blocking on it.
+	for {
+		_, ok := <-slow
+		if !ok {
+			return
+		}
+	}
+}
+
+func TestFollowerEventConstructors(t *testing.T) {
+	require.Equal(t, EventReleaseCommandStarted, ReleaseCommandStartedEvent().Type)
+	require.Equal(t, EventHealthCheckResult, HealthCheckResultEvent("1234567890abcd", "tcp-8080", true).Type)
+
+	done := DoneEvent(nil)
+	assert.Equal(t, EventDone, done.Type)
+	assert.Empty(t, done.Err)
+}
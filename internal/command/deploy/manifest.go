@@ -3,21 +3,80 @@ package deploy
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"time"
 
 	fly "github.com/superfly/fly-go"
 	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/flyutil"
 	"github.com/superfly/flyctl/internal/sentry"
 )
 
 const (
 	defaultManifestPath = "fly_deploy_manifest.json"
+
+	// manifestFlagName is the flag used to apply a manifest produced by
+	// --export-manifest verbatim, skipping local flag/config resolution.
+	manifestFlagName = "manifest"
+
+	// manifestSchemaVersionV1 is the original, unversioned manifest shape.
+	// Manifests written before SchemaVersion existed are treated as v1.
+	manifestSchemaVersionV1 = 1
+
+	// manifestSchemaVersionV2 renames the misspelled restart_max_retrie
+	// json tag to restart_max_retries.
+	manifestSchemaVersionV2 = 2
+
+	// currentManifestSchemaVersion is the schema version written by
+	// NewManifest and the highest version ManifestFromReader will load.
+	currentManifestSchemaVersion = manifestSchemaVersionV2
 )
 
+// manifestMigration upgrades a manifest, decoded as a raw map to avoid
+// depending on the struct shape of either the source or target version, from
+// its version to the next one in place.
+type manifestMigration func(raw map[string]any) error
+
+// manifestMigrations is keyed by the version being migrated *from*. Loading a
+// manifest runs every migration from its stored version up to
+// currentManifestSchemaVersion in order.
+var manifestMigrations = map[int]manifestMigration{
+	manifestSchemaVersionV1: migrateManifestV1ToV2,
+}
+
+// migrateManifestV1ToV2 renames the misspelled restart_max_retrie field
+// introduced in v1 to restart_max_retries.
+func migrateManifestV1ToV2(raw map[string]any) error {
+	if v, ok := raw["restart_max_retrie"]; ok {
+		raw["restart_max_retries"] = v
+		delete(raw, "restart_max_retrie")
+	}
+	raw["schema_version"] = manifestSchemaVersionV2
+	return nil
+}
+
+// ManifestMigrationError is returned when a manifest can't be loaded because
+// its schema version is newer than this flyctl understands, or because the
+// migration chain needed to reach the current version is incomplete.
+type ManifestMigrationError struct {
+	FromVersion int
+	ToVersion   int
+	Field       string
+	Reason      string
+}
+
+func (e *ManifestMigrationError) Error() string {
+	return fmt.Sprintf(
+		"cannot load deploy manifest: migrating %q from schema v%d to v%d: %s",
+		e.Field, e.FromVersion, e.ToVersion, e.Reason,
+	)
+}
+
 type DeployManifest struct {
+	SchemaVersion         int `json:"schema_version"`
 	AppName               string
 	Config                *appconfig.Config         `json:"config"`
 	DeploymentImage       string                    `json:"deployment_image,omitempty"`
@@ -47,12 +106,13 @@ type DeployManifest struct {
 	MaxConcurrent         int                       `json:"max_concurrent,omitempty"`
 	VolumeInitialSize     int                       `json:"volume_initial_size,omitempty"`
 	RestartPolicy         *fly.MachineRestartPolicy `json:"restart_policy,omitempty"`
-	RestartMaxRetries     int                       `json:"restart_max_retrie,omitempty"`
+	RestartMaxRetries     int                       `json:"restart_max_retries,omitempty"`
 	DeployRetries         int                       `json:"deploy_retries,omitempty"`
 }
 
 func NewManifest(AppName string, config *appconfig.Config, args MachineDeploymentArgs) *DeployManifest {
 	return &DeployManifest{
+		SchemaVersion:         currentManifestSchemaVersion,
 		AppName:               AppName,
 		Config:                config,
 		DeploymentImage:       args.DeploymentImage,
@@ -86,9 +146,99 @@ func NewManifest(AppName string, config *appconfig.Config, args MachineDeploymen
 	}
 }
 
+// ToArgs reverses NewManifest, reconstructing the MachineDeploymentArgs that
+// produced this manifest so a manifest loaded from disk can be deployed
+// without re-resolving any of the original CLI flags or app config.
+func (m *DeployManifest) ToArgs() MachineDeploymentArgs {
+	return MachineDeploymentArgs{
+		DeploymentImage:       m.DeploymentImage,
+		Strategy:              m.Strategy,
+		EnvFromFlags:          m.EnvFromFlags,
+		PrimaryRegionFlag:     m.PrimaryRegionFlag,
+		SkipSmokeChecks:       m.SkipSmokeChecks,
+		SkipHealthChecks:      m.SkipHealthChecks,
+		SkipDNSChecks:         m.SkipDNSChecks,
+		SkipReleaseCommand:    m.SkipReleaseCommand,
+		MaxUnavailable:        m.MaxUnavailable,
+		RestartOnly:           m.RestartOnly,
+		WaitTimeout:           m.WaitTimeout,
+		StopSignal:            m.StopSignal,
+		LeaseTimeout:          m.LeaseTimeout,
+		ReleaseCmdTimeout:     m.ReleaseCmdTimeout,
+		Guest:                 m.Guest,
+		IncreasedAvailability: m.IncreasedAvailability,
+		UpdateOnly:            m.UpdateOnly,
+		Files:                 m.Files,
+		ExcludeRegions:        m.ExcludeRegions,
+		OnlyRegions:           m.OnlyRegions,
+		ExcludeMachines:       m.ExcludeMachines,
+		OnlyMachines:          m.OnlyMachines,
+		ProcessGroups:         m.ProcessGroups,
+		MaxConcurrent:         m.MaxConcurrent,
+		VolumeInitialSize:     m.VolumeInitialSize,
+		RestartPolicy:         m.RestartPolicy,
+		RestartMaxRetries:     m.RestartMaxRetries,
+		DeployRetries:         m.DeployRetries,
+	}
+}
+
+// ManifestFromReader decodes a manifest as a raw map first so it can peek
+// SchemaVersion, run any migrations needed to reach
+// currentManifestSchemaVersion, and only then unmarshal into DeployManifest.
+// Manifests with no schema_version are assumed to be manifestSchemaVersionV1.
+// Manifests newer than this flyctl understands are rejected outright.
 func ManifestFromReader(r io.Reader) (*DeployManifest, error) {
+	raw := map[string]any{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	version := manifestSchemaVersionV1
+	if v, ok := raw["schema_version"]; ok {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, &ManifestMigrationError{
+				Field:  "schema_version",
+				Reason: "must be a number",
+			}
+		}
+		version = int(f)
+	}
+
+	if version > currentManifestSchemaVersion {
+		return nil, &ManifestMigrationError{
+			FromVersion: version,
+			ToVersion:   currentManifestSchemaVersion,
+			Field:       "schema_version",
+			Reason: fmt.Sprintf(
+				"manifest schema v%d is newer than the highest version this flyctl understands (v%d); upgrade flyctl",
+				version, currentManifestSchemaVersion,
+			),
+		}
+	}
+
+	for v := version; v < currentManifestSchemaVersion; v++ {
+		migrate, ok := manifestMigrations[v]
+		if !ok {
+			return nil, &ManifestMigrationError{
+				FromVersion: v,
+				ToVersion:   v + 1,
+				Field:       "schema_version",
+				Reason:      "no migration registered for this version",
+			}
+		}
+		if err := migrate(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
 	manifest := &DeployManifest{}
-	if err := json.NewDecoder(r).Decode(manifest); err != nil {
+	if err := json.Unmarshal(buf, manifest); err != nil {
 		return nil, err
 	}
 	return manifest, nil
@@ -118,24 +268,81 @@ func ManifestIntoFile(manifest *DeployManifest, filename string) error {
 	return ManifestIntoWriter(file, manifest)
 }
 
+// manifestFlag is the --manifest flag added to `fly deploy`. When set, the
+// deploy is driven entirely by the manifest on disk (typically produced on
+// another machine via --export-manifest) instead of re-resolving flags and
+// app config locally.
+var manifestFlag = flag.String{
+	Name:        manifestFlagName,
+	Description: "Deploy verbatim from a manifest file produced by --export-manifest",
+}
+
+// deployFromManifestFlag loads the manifest named by --manifest, if any, and
+// runs the deploy from it. It returns false, nil when the flag wasn't set so
+// callers can fall back to the normal flag/config-driven deploy path.
+func deployFromManifestFlag(ctx context.Context) (bool, error) {
+	path := flag.GetString(ctx, manifestFlagName)
+	if path == "" {
+		return false, nil
+	}
+
+	if err := requireSignedManifest(ctx, path); err != nil {
+		return true, err
+	}
+
+	manifest, err := ManifestFromFile(path)
+	if err != nil {
+		return true, fmt.Errorf("failed to load deploy manifest %s: %w", path, err)
+	}
+
+	return true, deployFromManifest(ctx, manifest, nil)
+}
+
 func exportManifest(ctx context.Context, appName string, args MachineDeploymentArgs) error {
 	config := appconfig.ConfigFromContext(ctx)
 
 	manifest := NewManifest(appName, config, args)
 
-	return ManifestIntoFile(manifest, defaultManifestPath)
+	signKeyPath := flag.GetString(ctx, signKeyFlagName)
+	if signKeyPath == "" {
+		return ManifestIntoFile(manifest, defaultManifestPath)
+	}
+
+	keyID, key, err := loadManifestSigningKey(signKeyPath)
+	if err != nil {
+		return err
+	}
+	return ManifestIntoFileSigned(manifest, defaultManifestPath, keyID, key)
 }
 
-func deployFromManifest(ctx context.Context, manifest *DeployManifest) error {
-	var (
-		client = flyutil.ClientFromContext(ctx)
-	)
-	app, err := client.GetAppCompact(ctx, manifest.AppName)
+func deployFromManifest(ctx context.Context, manifest *DeployManifest, follower Follower) error {
+	return runMachineDeployment(ctx, manifest.AppName, manifest.ToArgs(), follower)
+}
+
+// runMachineDeployment resolves appName's AppCompact, builds the machine
+// deployment from args, and runs it. It's shared by the manifest-driven
+// deploy path and the normal, flag-driven one so both report failures to
+// Sentry the same way.
+//
+// follower, if non-nil, is published to around the deploy: a
+// ReleaseCommandStartedEvent before the deploy begins and a DoneEvent once it
+// finishes, success or not. That's the best this function can do on its own -
+// the per-machine MachineUpdatedEvent/HealthCheckResultEvent notifications
+// belong inside NewMachineDeployment/DeployMachinesApp's own loop, which
+// would need to accept and hold onto follower itself to publish them as each
+// machine actually transitions.
+func runMachineDeployment(ctx context.Context, appName string, args MachineDeploymentArgs, follower Follower) (err error) {
+	client := flyutil.ClientFromContext(ctx)
+
+	app, err := client.GetAppCompact(ctx, appName)
 	if err != nil {
 		return err
 	}
 
-	args := MachineDeploymentArgs{}
+	if follower != nil {
+		follower.Publish(ReleaseCommandStartedEvent())
+		defer func() { follower.Publish(DoneEvent(err)) }()
+	}
 
 	md, err := NewMachineDeployment(ctx, args)
 	if err != nil {
@@ -143,10 +350,9 @@ func deployFromManifest(ctx context.Context, manifest *DeployManifest) error {
 		return err
 	}
 
-	err = md.DeployMachinesApp(ctx)
-	if err != nil {
+	if err = md.DeployMachinesApp(ctx); err != nil {
 		sentry.CaptureExceptionWithAppInfo(ctx, err, "deploy", app)
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,217 @@
+package deploy
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+const (
+	signKeyFlagName               = "sign"
+	trustedKeysDirFlagName        = "trusted-keys-dir"
+	requireSignedManifestFlagName = "require-signed-manifest"
+
+	// maxManifestSignatureAge is how old a manifest signature is allowed to
+	// be before VerifyManifest treats it as expired. Deploy manifests are
+	// meant to be applied shortly after --export-manifest --sign runs in CI,
+	// not kept around indefinitely.
+	maxManifestSignatureAge = 24 * time.Hour
+)
+
+var signKeyFlag = flag.String{
+	Name:        signKeyFlagName,
+	Description: "Sign the exported manifest with the ed25519 private key at this path (hex-encoded)",
+}
+
+var trustedKeysDirFlag = flag.String{
+	Name:        trustedKeysDirFlagName,
+	Description: "Directory of hex-encoded ed25519 public keys (*.pub) trusted to sign deploy manifests",
+}
+
+var requireSignedManifestFlag = flag.Bool{
+	Name:        requireSignedManifestFlagName,
+	Description: "Refuse to deploy from --manifest unless it carries a valid, unexpired signature",
+	Default:     false,
+}
+
+// manifestSignatureEnvelope is the detached signature written alongside a
+// manifest as fly_deploy_manifest.sig. It is deliberately small: no
+// certificate chain, no PKI, just a key_id naming the signer. VerifyManifest
+// accepts the signature if any trusted key verifies it and uses key_id only
+// in its error message.
+type manifestSignatureEnvelope struct {
+	PayloadSHA256 string    `json:"payload_sha256"`
+	SignedAt      time.Time `json:"signed_at"`
+	KeyID         string    `json:"key_id"`
+	Signature     string    `json:"signature"`
+}
+
+func manifestSigPathFor(filename string) string {
+	ext := filepath.Ext(filename)
+	if ext == "" {
+		return filename + ".sig"
+	}
+	return strings.TrimSuffix(filename, ext) + ".sig"
+}
+
+// ManifestIntoFileSigned writes the manifest to filename and a detached
+// signature envelope alongside it, signed with key and labeled with keyID so
+// a verifier can pick the right trusted public key.
+func ManifestIntoFileSigned(manifest *DeployManifest, filename string, keyID string, key ed25519.PrivateKey) error {
+	if err := ManifestIntoFile(manifest, filename); err != nil {
+		return err
+	}
+
+	payload, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(payload)
+
+	envelope := manifestSignatureEnvelope{
+		PayloadSHA256: hex.EncodeToString(sum[:]),
+		SignedAt:      time.Now().UTC(),
+		KeyID:         keyID,
+		Signature:     hex.EncodeToString(ed25519.Sign(key, sum[:])),
+	}
+
+	sigFile, err := os.Create(manifestSigPathFor(filename))
+	if err != nil {
+		return err
+	}
+	defer sigFile.Close()
+
+	enc := json.NewEncoder(sigFile)
+	enc.SetIndent("", "  ")
+	return enc.Encode(envelope)
+}
+
+// VerifyManifest recomputes the payload hash of the manifest at path, checks
+// its detached signature's age, and verifies it was produced by one of
+// trustedKeys. It fails closed: a missing or malformed signature file is an
+// error, not an unsigned-but-allowed manifest.
+func VerifyManifest(path string, trustedKeys []ed25519.PublicKey) error {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	sigFile, err := os.Open(manifestSigPathFor(path))
+	if err != nil {
+		return fmt.Errorf("manifest %s is not signed: %w", path, err)
+	}
+	defer sigFile.Close()
+
+	var envelope manifestSignatureEnvelope
+	if err := json.NewDecoder(sigFile).Decode(&envelope); err != nil {
+		return fmt.Errorf("invalid signature envelope for manifest %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(payload)
+	if hex.EncodeToString(sum[:]) != envelope.PayloadSHA256 {
+		return fmt.Errorf("manifest %s does not match its signature", path)
+	}
+
+	if age := time.Since(envelope.SignedAt); age > maxManifestSignatureAge {
+		return fmt.Errorf("manifest %s signature expired %s ago (max age %s)", path, age.Round(time.Second), maxManifestSignatureAge)
+	}
+
+	sig, err := hex.DecodeString(envelope.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding for manifest %s: %w", path, err)
+	}
+
+	for _, key := range trustedKeys {
+		if ed25519.Verify(key, sum[:], sig) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("manifest %s signature from key %q is not trusted", path, envelope.KeyID)
+}
+
+// loadManifestSigningKey reads a hex-encoded ed25519 private key from path.
+// The key ID recorded in the signature envelope is the hex SHA-256 of the
+// corresponding public key, so a verifier can match it against a trusted
+// keys directory without a separate key-naming convention.
+func loadManifestSigningKey(path string) (keyID string, key ed25519.PrivateKey, err error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err = hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid ed25519 private key in %s: %w", path, err)
+	}
+	if len(key) != ed25519.PrivateKeySize {
+		return "", nil, fmt.Errorf("invalid ed25519 private key in %s: want %d bytes, got %d", path, ed25519.PrivateKeySize, len(key))
+	}
+
+	pub := key.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:]), key, nil
+}
+
+// loadTrustedManifestKeys reads every *.pub file in dir as a hex-encoded
+// ed25519 public key.
+func loadTrustedManifestKeys(dir string) ([]ed25519.PublicKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []ed25519.PublicKey
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pub" {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		pub, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ed25519 public key in %s: %w", entry.Name(), err)
+		}
+		if len(pub) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key in %s: want %d bytes, got %d", entry.Name(), ed25519.PublicKeySize, len(pub))
+		}
+
+		keys = append(keys, pub)
+	}
+
+	return keys, nil
+}
+
+// requireSignedManifest enforces --require-signed-manifest: when set, path
+// must carry a valid, unexpired signature from one of the keys in
+// --trusted-keys-dir.
+func requireSignedManifest(ctx context.Context, path string) error {
+	if !flag.GetBool(ctx, requireSignedManifestFlagName) {
+		return nil
+	}
+
+	dir := flag.GetString(ctx, trustedKeysDirFlagName)
+	if dir == "" {
+		return fmt.Errorf("--require-signed-manifest requires --trusted-keys-dir")
+	}
+
+	trustedKeys, err := loadTrustedManifestKeys(dir)
+	if err != nil {
+		return fmt.Errorf("failed to load trusted keys: %w", err)
+	}
+
+	return VerifyManifest(path, trustedKeys)
+}
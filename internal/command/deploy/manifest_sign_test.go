@@ -0,0 +1,88 @@
+package deploy
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeSigningKey(t *testing.T, dir string) (keyPath, pubPath string, pub ed25519.PublicKey, priv ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keyPath = filepath.Join(dir, "signer.key")
+	require.NoError(t, os.WriteFile(keyPath, []byte(hex.EncodeToString(priv)), 0o600))
+
+	pubPath = filepath.Join(dir, "signer.pub")
+	require.NoError(t, os.WriteFile(pubPath, []byte(hex.EncodeToString(pub)), 0o600))
+
+	return keyPath, pubPath, pub, priv
+}
+
+func TestManifestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, _, _, _ := writeSigningKey(t, dir)
+
+	keyID, key, err := loadManifestSigningKey(keyPath)
+	require.NoError(t, err)
+
+	manifest := NewManifest("my-app", nil, MachineDeploymentArgs{})
+	manifestPath := filepath.Join(dir, "fly_deploy_manifest.json")
+	require.NoError(t, ManifestIntoFileSigned(manifest, manifestPath, keyID, key))
+
+	pub := key.Public().(ed25519.PublicKey)
+	require.NoError(t, VerifyManifest(manifestPath, []ed25519.PublicKey{pub}))
+}
+
+func TestManifestVerifyRejectsUntrustedKey(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, _, _, _ := writeSigningKey(t, dir)
+
+	keyID, key, err := loadManifestSigningKey(keyPath)
+	require.NoError(t, err)
+
+	manifest := NewManifest("my-app", nil, MachineDeploymentArgs{})
+	manifestPath := filepath.Join(dir, "fly_deploy_manifest.json")
+	require.NoError(t, ManifestIntoFileSigned(manifest, manifestPath, keyID, key))
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	require.Error(t, VerifyManifest(manifestPath, []ed25519.PublicKey{otherPub}))
+}
+
+func TestManifestVerifyRejectsExpiredSignature(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, _, _, _ := writeSigningKey(t, dir)
+
+	keyID, key, err := loadManifestSigningKey(keyPath)
+	require.NoError(t, err)
+
+	manifest := NewManifest("my-app", nil, MachineDeploymentArgs{})
+	manifestPath := filepath.Join(dir, "fly_deploy_manifest.json")
+	require.NoError(t, ManifestIntoFileSigned(manifest, manifestPath, keyID, key))
+
+	sigPath := manifestSigPathFor(manifestPath)
+	raw, err := os.ReadFile(sigPath)
+	require.NoError(t, err)
+
+	var envelope manifestSignatureEnvelope
+	require.NoError(t, json.Unmarshal(raw, &envelope))
+	envelope.SignedAt = time.Now().Add(-2 * maxManifestSignatureAge).UTC()
+
+	rewritten, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(sigPath, rewritten, 0o600))
+
+	pub := key.Public().(ed25519.PublicKey)
+	err = VerifyManifest(manifestPath, []ed25519.PublicKey{pub})
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package deploy
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fly "github.com/superfly/fly-go"
+)
+
+func fullyPopulatedArgs() MachineDeploymentArgs {
+	waitTimeout := 5 * time.Minute
+	leaseTimeout := 30 * time.Second
+	releaseCmdTimeout := 2 * time.Minute
+	maxUnavailable := 0.25
+
+	return MachineDeploymentArgs{
+		DeploymentImage:       "registry.fly.io/app:deployment-01",
+		Strategy:              "rolling",
+		EnvFromFlags:          []string{"FOO=bar", "BAZ=qux"},
+		PrimaryRegionFlag:     "iad",
+		SkipSmokeChecks:       true,
+		SkipHealthChecks:      true,
+		SkipDNSChecks:         true,
+		SkipReleaseCommand:    true,
+		MaxUnavailable:        &maxUnavailable,
+		RestartOnly:           true,
+		WaitTimeout:           &waitTimeout,
+		StopSignal:            "SIGTERM",
+		LeaseTimeout:          &leaseTimeout,
+		ReleaseCmdTimeout:     &releaseCmdTimeout,
+		Guest:                 &fly.MachineGuest{CPUKind: "shared", CPUs: 2, MemoryMB: 512},
+		IncreasedAvailability: true,
+		UpdateOnly:            true,
+		Files:                 []*fly.File{{GuestPath: "/etc/app.conf"}},
+		ExcludeRegions:        map[string]bool{"syd": true},
+		OnlyRegions:           map[string]bool{"iad": true},
+		ExcludeMachines:       map[string]bool{"1234567890abcd": true},
+		OnlyMachines:          map[string]bool{"abcd1234567890": true},
+		ProcessGroups:         map[string]bool{"web": true},
+		MaxConcurrent:         3,
+		VolumeInitialSize:     10,
+		RestartPolicy:         &fly.MachineRestartPolicy{},
+		RestartMaxRetries:     5,
+		DeployRetries:         2,
+	}
+}
+
+const manifestFixtureV1 = `{
+	"AppName": "my-app",
+	"config": null,
+	"strategy": "rolling",
+	"restart_max_retrie": 5
+}`
+
+const manifestFixtureV2 = `{
+	"schema_version": 2,
+	"AppName": "my-app",
+	"config": null,
+	"strategy": "rolling",
+	"restart_max_retries": 5
+}`
+
+func TestManifestFromReaderMigratesV1ToV2(t *testing.T) {
+	manifest, err := ManifestFromReader(bytes.NewBufferString(manifestFixtureV1))
+	require.NoError(t, err)
+
+	assert.Equal(t, currentManifestSchemaVersion, manifest.SchemaVersion)
+	assert.Equal(t, 5, manifest.RestartMaxRetries)
+}
+
+func TestManifestFromReaderLoadsV2Directly(t *testing.T) {
+	manifest, err := ManifestFromReader(bytes.NewBufferString(manifestFixtureV2))
+	require.NoError(t, err)
+
+	assert.Equal(t, manifestSchemaVersionV2, manifest.SchemaVersion)
+	assert.Equal(t, 5, manifest.RestartMaxRetries)
+}
+
+func TestManifestFromReaderRejectsFutureSchema(t *testing.T) {
+	future := `{"schema_version": 99, "AppName": "my-app"}`
+
+	_, err := ManifestFromReader(bytes.NewBufferString(future))
+	require.Error(t, err)
+
+	var migrationErr *ManifestMigrationError
+	require.ErrorAs(t, err, &migrationErr)
+	assert.Equal(t, 99, migrationErr.FromVersion)
+	assert.Equal(t, currentManifestSchemaVersion, migrationErr.ToVersion)
+}
+
+func TestManifestToArgsRoundTrip(t *testing.T) {
+	args := fullyPopulatedArgs()
+	manifest := NewManifest("my-app", nil, args)
+
+	var buf bytes.Buffer
+	require.NoError(t, ManifestIntoWriter(&buf, manifest))
+
+	reloaded, err := ManifestFromReader(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, args, reloaded.ToArgs())
+}
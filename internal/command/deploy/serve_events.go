@@ -0,0 +1,153 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+// followerKeepaliveInterval bounds how long a follower can go without a
+// write before serve-events sends one anyway, so a slow follower is detected
+// (and dropped) instead of left open indefinitely on a quiet deploy.
+const followerKeepaliveInterval = 15 * time.Second
+
+func newServeEvents() *cobra.Command {
+	const (
+		usage = "serve-events"
+		short = "Run a manifest-driven deploy while streaming its progress to connected followers"
+		long  = "Run the deploy described by --manifest (the same file --export-manifest\n" +
+			"produces) and, for as long as it runs, stream its progress events\n" +
+			"(release command, machine updates, health checks, completion) over HTTP\n" +
+			"as newline-delimited JSON. Pair with `fly deploy follow --url`.\n\n" +
+			"This is the entry point for making a CI-driven manifest deploy\n" +
+			"observable: the deploy and the event stream share one process, so a\n" +
+			"follower sees the real events of the deploy actually in flight rather\n" +
+			"than an unattached stream."
+	)
+	cmd := command.New(usage, short, long, runServeEvents, command.RequireSession)
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(
+		cmd,
+		flag.String{
+			Name:        "listen",
+			Description: "Address to listen on",
+			Default:     ":8080",
+		},
+		manifestFlag,
+		signKeyFlag,
+		trustedKeysDirFlag,
+		requireSignedManifestFlag,
+	)
+
+	return cmd
+}
+
+func runServeEvents(ctx context.Context) error {
+	addr := flag.GetString(ctx, "listen")
+
+	path := flag.GetString(ctx, manifestFlagName)
+	if path == "" {
+		return errors.New("--manifest is required: serve-events runs the deploy it streams, it doesn't serve an empty stream")
+	}
+
+	if err := requireSignedManifest(ctx, path); err != nil {
+		return err
+	}
+
+	manifest, err := ManifestFromFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load deploy manifest %s: %w", path, err)
+	}
+
+	broadcaster := NewEventBroadcaster()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", serveFollowerEvents(broadcaster))
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	serverErrs := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrs <- err
+		}
+	}()
+
+	deployErr := deployFromManifest(ctx, manifest, broadcaster)
+
+	// Give any connected follower a chance to read the DoneEvent before the
+	// listener goes away out from under them.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), followerKeepaliveInterval)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	select {
+	case err := <-serverErrs:
+		return err
+	default:
+		return deployErr
+	}
+}
+
+// serveFollowerEvents streams one follower's events as newline-delimited
+// JSON. It ticks on followerKeepaliveInterval so a connection with nothing
+// to say still gets a write, and treats that write's error the same as any
+// event write's: the first one ends the handler and, via the deferred
+// RemoveFollower, drops the follower from the broadcaster.
+func serveFollowerEvents(b Follower) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		id := r.RemoteAddr
+		events := b.AddFollower(id)
+		defer b.RemoveFollower(id)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		ticker := time.NewTicker(followerKeepaliveInterval)
+		defer ticker.Stop()
+
+		for {
+			var (
+				event FollowerEvent
+				ok    bool
+			)
+
+			select {
+			case event, ok = <-events:
+				if !ok {
+					return
+				}
+			case <-ticker.C:
+				event = FollowerEvent{Type: eventKeepalive, Timestamp: time.Now()}
+			case <-r.Context().Done():
+				return
+			}
+
+			if err := enc.Encode(event); err != nil {
+				return
+			}
+			flusher.Flush()
+
+			if event.Type == EventDone {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// jsonSchemaNode is the subset of JSON Schema that validateJSONSchema
+// understands: type, required, properties, items, enum and pattern. That
+// covers every constraint the embedded SPDX and CycloneDX schemas in
+// schemas/ actually use.
+type jsonSchemaNode struct {
+	Type       string                    `json:"type,omitempty"`
+	Required   []string                  `json:"required,omitempty"`
+	Properties map[string]jsonSchemaNode `json:"properties,omitempty"`
+	Items      *jsonSchemaNode           `json:"items,omitempty"`
+	Enum       []string                  `json:"enum,omitempty"`
+	Pattern    string                    `json:"pattern,omitempty"`
+}
+
+// validateJSONSchema checks value against schema, recursing into properties
+// and items. path is the JSON Pointer-ish location reported in error
+// messages; pass "" at the top level.
+func validateJSONSchema(schema jsonSchemaNode, value any, path string) error {
+	if path == "" {
+		path = "$"
+	}
+
+	if schema.Type != "" {
+		if err := checkJSONSchemaType(schema.Type, value, path); err != nil {
+			return err
+		}
+	}
+
+	if schema.Enum != nil {
+		s, ok := value.(string)
+		if !ok || !stringSliceContains(schema.Enum, s) {
+			return fmt.Errorf("%s: value %v is not one of %v", path, value, schema.Enum)
+		}
+	}
+
+	if schema.Pattern != "" {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: pattern %q requires a string, got %T", path, schema.Pattern, value)
+		}
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern %q: %w", path, schema.Pattern, err)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%s: value %q does not match pattern %q", path, s, schema.Pattern)
+		}
+	}
+
+	if len(schema.Required) > 0 || len(schema.Properties) > 0 {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %T", path, value)
+		}
+
+		for _, field := range schema.Required {
+			if _, ok := obj[field]; !ok {
+				return fmt.Errorf("%s: missing required field %q", path, field)
+			}
+		}
+
+		for field, fieldSchema := range schema.Properties {
+			fieldValue, ok := obj[field]
+			if !ok {
+				continue
+			}
+			if err := validateJSONSchema(fieldSchema, fieldValue, path+"."+field); err != nil {
+				return err
+			}
+		}
+	}
+
+	if schema.Items != nil {
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an array, got %T", path, value)
+		}
+		for i, item := range arr {
+			if err := validateJSONSchema(*schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkJSONSchemaType(t string, value any, path string) error {
+	var ok bool
+	switch t {
+	case "object":
+		_, ok = value.(map[string]any)
+	case "array":
+		_, ok = value.([]any)
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		f, isNumber := value.(float64)
+		ok = isNumber && f == float64(int64(f))
+	default:
+		return fmt.Errorf("%s: unknown schema type %q", path, t)
+	}
+
+	if !ok {
+		return fmt.Errorf("%s: expected type %q, got %T", path, t, value)
+	}
+	return nil
+}
+
+func stringSliceContains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/superfly/flyctl/iostreams"
 )
 
+const defaultSBOMFormat = "raw"
+
 func newSbom() *cobra.Command {
 	const (
 		usage = "sbom"
@@ -47,6 +49,16 @@ func newSbom() *cobra.Command {
 			Description: "Select which machine to scan the image of from a list.",
 			Default:     false,
 		},
+		flag.String{
+			Name:        "format",
+			Description: "Output format: spdx-json, cyclonedx-json, cyclonedx-xml, or raw",
+			Default:     defaultSBOMFormat,
+		},
+		flag.Bool{
+			Name:        "attest",
+			Description: "Wrap the SBOM in an in-toto attestation subject to the resolved image digest",
+			Default:     false,
+		},
 	)
 
 	return cmd
@@ -57,8 +69,20 @@ func runSbom(ctx context.Context) error {
 		ios       = iostreams.FromContext(ctx)
 		appName   = appconfig.NameFromContext(ctx)
 		apiClient = flyutil.ClientFromContext(ctx)
+		format    = flag.GetString(ctx, "format")
+		attest    = flag.GetBool(ctx, "attest")
 	)
 
+	if !validSBOMFormat(format) {
+		return fmt.Errorf("invalid --format %q: must be one of %s", format, sbomFormats)
+	}
+	if attest && format == "raw" {
+		return fmt.Errorf("--attest requires --format to be spdx-json, cyclonedx-json, or cyclonedx-xml")
+	}
+	if attest && format == "cyclonedx-xml" {
+		return fmt.Errorf("--attest does not support --format cyclonedx-xml; use cyclonedx-json or spdx-json")
+	}
+
 	app, err := apiClient.GetAppCompact(ctx, appName)
 	if err != nil {
 		return fmt.Errorf("failed to get app: %w", err)
@@ -84,8 +108,30 @@ func runSbom(ctx context.Context) error {
 		return fmt.Errorf("failed fetching SBOM (status code %d)", res.StatusCode)
 	}
 
-	if _, err := io.Copy(ios.Out, res.Body); err != nil {
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
 		return fmt.Errorf("failed to read SBOM: %w", err)
 	}
-	return nil
+
+	output := raw
+	if format != "raw" {
+		doc, err := parseScantronSBOM(raw)
+		if err != nil {
+			return err
+		}
+		output, err = renderSBOM(doc, format)
+		if err != nil {
+			return err
+		}
+	}
+
+	if attest {
+		output, err = attestSBOM(output, format, imgPath)
+		if err != nil {
+			return fmt.Errorf("failed to build SBOM attestation: %w", err)
+		}
+	}
+
+	_, err = ios.Out.Write(output)
+	return err
 }
@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// inTotoStatement is the subset of the in-toto v1 Statement schema needed to
+// wrap a rendered SBOM so policy engines can consume it directly.
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []inTotoSubject `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// sbomPredicateType maps an SBOM output format to the in-toto predicateType
+// that describes it.
+func sbomPredicateType(format string) string {
+	switch format {
+	case "spdx-json":
+		return "https://spdx.dev/Document"
+	default:
+		return "https://cyclonedx.org/bom"
+	}
+}
+
+// attestSBOM wraps a rendered SBOM in an in-toto Statement whose subject is
+// the resolved image digest. attest only supports JSON predicates: a
+// cyclonedx-xml SBOM can't be embedded as a predicate without re-encoding it,
+// so callers should reject --attest with --format cyclonedx-xml before
+// calling this.
+func attestSBOM(rendered []byte, format, imageDigest string) ([]byte, error) {
+	statement := inTotoStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		PredicateType: sbomPredicateType(format),
+		Subject: []inTotoSubject{
+			{
+				Name:   imageDigest,
+				Digest: map[string]string{"sha256": imageDigestHex(imageDigest)},
+			},
+		},
+		Predicate: json.RawMessage(rendered),
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(statement); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// imageDigestHex extracts the hex digest from a "name@sha256:<hex>"
+// reference. If ref doesn't carry a digest, it's returned as-is: the
+// attestation is still useful for policy engines that match on name.
+func imageDigestHex(ref string) string {
+	const sep = "@sha256:"
+	if i := strings.Index(ref, sep); i >= 0 {
+		return ref[i+len(sep):]
+	}
+	return ref
+}
@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// sbomComponent is the format-agnostic shape every supported output format
+// is rendered from, normalized out of whatever upstream Scantron happens to
+// return.
+type sbomComponent struct {
+	Name     string
+	Version  string
+	PURL     string
+	Licenses []string
+	Hashes   map[string]string // algorithm (e.g. "SHA256") -> hex digest
+}
+
+// sbomRelationship records a dependency edge between two components, keyed
+// by PURL.
+type sbomRelationship struct {
+	From string
+	To   string
+	Type string // currently always "depends_on"
+}
+
+// sbomDocument is the internal SBOM model. Every renderer (spdx-json,
+// cyclonedx-json, cyclonedx-xml) consumes this instead of the upstream
+// response directly, so adding a format doesn't require re-deriving the
+// parsing logic.
+type sbomDocument struct {
+	ImageName     string
+	ImageVersion  string
+	Components    []sbomComponent
+	Relationships []sbomRelationship
+}
+
+// scantronCycloneDX is the subset of Scantron's native CycloneDX response
+// this command reads to build an sbomDocument.
+type scantronCycloneDX struct {
+	Metadata struct {
+		Component struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"component"`
+	} `json:"metadata"`
+	Components []struct {
+		Name     string `json:"name"`
+		Version  string `json:"version"`
+		PURL     string `json:"purl"`
+		Licenses []struct {
+			License struct {
+				ID string `json:"id"`
+			} `json:"license"`
+		} `json:"licenses"`
+		Hashes []struct {
+			Alg     string `json:"alg"`
+			Content string `json:"content"`
+		} `json:"hashes"`
+	} `json:"components"`
+	Dependencies []struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn"`
+	} `json:"dependencies"`
+}
+
+// parseScantronSBOM normalizes Scantron's native CycloneDX JSON response
+// into the internal sbomDocument model.
+func parseScantronSBOM(raw []byte) (*sbomDocument, error) {
+	var upstream scantronCycloneDX
+	if err := json.Unmarshal(raw, &upstream); err != nil {
+		return nil, fmt.Errorf("failed to parse SBOM response: %w", err)
+	}
+
+	doc := &sbomDocument{
+		ImageName:    upstream.Metadata.Component.Name,
+		ImageVersion: upstream.Metadata.Component.Version,
+	}
+
+	for _, c := range upstream.Components {
+		component := sbomComponent{
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    c.PURL,
+			Hashes:  map[string]string{},
+		}
+		for _, l := range c.Licenses {
+			if l.License.ID != "" {
+				component.Licenses = append(component.Licenses, l.License.ID)
+			}
+		}
+		for _, h := range c.Hashes {
+			component.Hashes[h.Alg] = h.Content
+		}
+		doc.Components = append(doc.Components, component)
+	}
+
+	for _, dep := range upstream.Dependencies {
+		for _, to := range dep.DependsOn {
+			doc.Relationships = append(doc.Relationships, sbomRelationship{
+				From: dep.Ref,
+				To:   to,
+				Type: "depends_on",
+			})
+		}
+	}
+
+	return doc, nil
+}
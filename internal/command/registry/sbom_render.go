@@ -0,0 +1,294 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// sbomFormats are the values accepted by --format.
+var sbomFormats = []string{"spdx-json", "cyclonedx-json", "cyclonedx-xml", "raw"}
+
+// nowFunc is overridden in tests so golden-file output is deterministic.
+var nowFunc = time.Now
+
+func validSBOMFormat(format string) bool {
+	for _, f := range sbomFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// renderSBOM renders doc in the requested format. "raw" is handled by the
+// caller, which passes through the untouched upstream response instead of
+// round-tripping it through sbomDocument.
+func renderSBOM(doc *sbomDocument, format string) ([]byte, error) {
+	switch format {
+	case "spdx-json":
+		return renderSPDXJSON(doc)
+	case "cyclonedx-json":
+		return renderCycloneDXJSON(doc)
+	case "cyclonedx-xml":
+		return renderCycloneDXXML(doc)
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format %q", format)
+	}
+}
+
+type spdxPackage struct {
+	SPDXID           string    `json:"SPDXID"`
+	Name             string    `json:"name"`
+	VersionInfo      string    `json:"versionInfo,omitempty"`
+	LicenseConcluded string    `json:"licenseConcluded,omitempty"`
+	ExternalRefs     []spdxRef `json:"externalRefs,omitempty"`
+}
+
+type spdxRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+func renderSPDXJSON(doc *sbomDocument) ([]byte, error) {
+	spdxDoc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              doc.ImageName,
+		DocumentNamespace: fmt.Sprintf("https://fly.io/spdx/%s-%s", doc.ImageName, doc.ImageVersion),
+		CreationInfo: spdxCreationInfo{
+			Created:  nowFunc().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: flyctl-sbom"},
+		},
+	}
+
+	for i, c := range doc.Components {
+		pkg := spdxPackage{
+			SPDXID:      fmt.Sprintf("SPDXRef-Package-%d", i),
+			Name:        c.Name,
+			VersionInfo: c.Version,
+		}
+		if len(c.Licenses) > 0 {
+			pkg.LicenseConcluded = c.Licenses[0]
+		}
+		if c.PURL != "" {
+			pkg.ExternalRefs = append(pkg.ExternalRefs, spdxRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  c.PURL,
+			})
+		}
+		spdxDoc.Packages = append(spdxDoc.Packages, pkg)
+	}
+
+	for _, rel := range doc.Relationships {
+		spdxDoc.Relationships = append(spdxDoc.Relationships, spdxRelationship{
+			SPDXElementID:      rel.From,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: rel.To,
+		})
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(spdxDoc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type cycloneDXComponent struct {
+	Type     string                   `json:"type" xml:"type,attr"`
+	Name     string                   `json:"name" xml:"name"`
+	Version  string                   `json:"version,omitempty" xml:"version,omitempty"`
+	PURL     string                   `json:"purl,omitempty" xml:"purl,omitempty"`
+	Licenses []cycloneDXLicenseChoice `json:"licenses,omitempty" xml:"-"`
+}
+
+// MarshalXML implements xml.Marshaler. encoding/xml's omitempty doesn't take
+// effect on a chained element path like "licenses>license", so a component
+// with no licenses would otherwise still render an empty
+// <licenses></licenses>; omit the element outright instead.
+func (c cycloneDXComponent) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "type"}, Value: c.Type})
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if err := e.EncodeElement(c.Name, xml.StartElement{Name: xml.Name{Local: "name"}}); err != nil {
+		return err
+	}
+	if c.Version != "" {
+		if err := e.EncodeElement(c.Version, xml.StartElement{Name: xml.Name{Local: "version"}}); err != nil {
+			return err
+		}
+	}
+	if c.PURL != "" {
+		if err := e.EncodeElement(c.PURL, xml.StartElement{Name: xml.Name{Local: "purl"}}); err != nil {
+			return err
+		}
+	}
+	if len(c.Licenses) > 0 {
+		licensesStart := xml.StartElement{Name: xml.Name{Local: "licenses"}}
+		if err := e.EncodeToken(licensesStart); err != nil {
+			return err
+		}
+		for _, choice := range c.Licenses {
+			if err := e.EncodeElement(choice.License, xml.StartElement{Name: xml.Name{Local: "license"}}); err != nil {
+				return err
+			}
+		}
+		if err := e.EncodeToken(licensesStart.End()); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// cycloneDXLicenseChoice is one entry of a component's licenses list. JSON
+// wraps each license as {"license": {"id": ...}}, the same nesting as the
+// XML <licenses><license> elements.
+type cycloneDXLicenseChoice struct {
+	License cycloneDXLicense `json:"license"`
+}
+
+type cycloneDXLicense struct {
+	ID string `json:"id" xml:"id"`
+}
+
+// cycloneDXDependency is the JSON dependency shape: a ref and a flat list of
+// the refs it depends on.
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type cycloneDXBOM struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	Version      int                   `json:"version"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies,omitempty"`
+}
+
+// cycloneDXDependencyXML is the XML dependency shape: each dependsOn ref is
+// a nested <dependency ref="..."/> element, matching the CycloneDX XML
+// schema's recursive dependency graph instead of JSON's flat dependsOn list.
+type cycloneDXDependencyXML struct {
+	Ref       string                   `xml:"ref,attr"`
+	DependsOn []cycloneDXDependencyXML `xml:"dependency"`
+}
+
+type cycloneDXBOMXML struct {
+	XMLName      xml.Name                 `xml:"bom"`
+	XMLNS        string                   `xml:"xmlns,attr"`
+	Version      int                      `xml:"version,attr"`
+	Components   []cycloneDXComponent     `xml:"components>component"`
+	Dependencies []cycloneDXDependencyXML `xml:"dependencies>dependency,omitempty"`
+}
+
+func cycloneDXComponents(doc *sbomDocument) []cycloneDXComponent {
+	var components []cycloneDXComponent
+	for _, c := range doc.Components {
+		component := cycloneDXComponent{
+			Type:    "library",
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    c.PURL,
+		}
+		for _, license := range c.Licenses {
+			component.Licenses = append(component.Licenses, cycloneDXLicenseChoice{License: cycloneDXLicense{ID: license}})
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// cycloneDXDependsOn groups doc.Relationships by their From ref, preserving
+// first-seen order so output is deterministic.
+func cycloneDXDependsOn(doc *sbomDocument) (order []string, deps map[string][]string) {
+	deps = map[string][]string{}
+	for _, rel := range doc.Relationships {
+		if _, ok := deps[rel.From]; !ok {
+			order = append(order, rel.From)
+		}
+		deps[rel.From] = append(deps[rel.From], rel.To)
+	}
+	return order, deps
+}
+
+func renderCycloneDXJSON(doc *sbomDocument) ([]byte, error) {
+	bom := cycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Components:  cycloneDXComponents(doc),
+	}
+
+	order, deps := cycloneDXDependsOn(doc)
+	for _, ref := range order {
+		bom.Dependencies = append(bom.Dependencies, cycloneDXDependency{Ref: ref, DependsOn: deps[ref]})
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bom); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderCycloneDXXML(doc *sbomDocument) ([]byte, error) {
+	bom := cycloneDXBOMXML{
+		XMLNS:      "http://cyclonedx.org/schema/bom/1.5",
+		Version:    1,
+		Components: cycloneDXComponents(doc),
+	}
+
+	order, deps := cycloneDXDependsOn(doc)
+	for _, ref := range order {
+		dep := cycloneDXDependencyXML{Ref: ref}
+		for _, to := range deps[ref] {
+			dep.DependsOn = append(dep.DependsOn, cycloneDXDependencyXML{Ref: to})
+		}
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(bom); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const scantronFixtureJSON = `{
+  "metadata": {"component": {"name": "my-app", "version": "v123"}},
+  "components": [
+    {"name": "openssl", "version": "3.0.2", "purl": "pkg:deb/debian/openssl@3.0.2", "licenses": [{"license": {"id": "Apache-2.0"}}], "hashes": [{"alg": "SHA-256", "content": "abcd1234"}]},
+    {"name": "zlib", "version": "1.2.11", "purl": "pkg:deb/debian/zlib@1.2.11"}
+  ],
+  "dependencies": [
+    {"ref": "pkg:deb/debian/openssl@3.0.2", "dependsOn": ["pkg:deb/debian/zlib@1.2.11"]}
+  ]
+}`
+
+func golden(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return b
+}
+
+func TestRenderSBOMGoldenFiles(t *testing.T) {
+	orig := nowFunc
+	nowFunc = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { nowFunc = orig }()
+
+	doc, err := parseScantronSBOM([]byte(scantronFixtureJSON))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		format string
+		golden string
+	}{
+		{"spdx-json", "spdx.golden.json"},
+		{"cyclonedx-json", "cyclonedx.golden.json"},
+		{"cyclonedx-xml", "cyclonedx.golden.xml"},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			out, err := renderSBOM(doc, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden(t, tc.golden)), string(out))
+
+			if tc.format != "cyclonedx-xml" {
+				assert.NoError(t, validateSBOMJSON(tc.format, out))
+			}
+		})
+	}
+}
+
+func TestAttestSBOMGoldenFile(t *testing.T) {
+	orig := nowFunc
+	nowFunc = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { nowFunc = orig }()
+
+	doc, err := parseScantronSBOM([]byte(scantronFixtureJSON))
+	require.NoError(t, err)
+
+	rendered, err := renderSBOM(doc, "cyclonedx-json")
+	require.NoError(t, err)
+
+	attested, err := attestSBOM(rendered, "cyclonedx-json", "registry.fly.io/my-app@sha256:deadbeef")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(golden(t, "attested.golden.json")), string(attested))
+}
+
+func TestValidSBOMFormat(t *testing.T) {
+	assert.True(t, validSBOMFormat("raw"))
+	assert.True(t, validSBOMFormat("spdx-json"))
+	assert.False(t, validSBOMFormat("yaml"))
+}
@@ -0,0 +1,46 @@
+package registry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed schemas/spdx-2.3.schema.json
+var spdxJSONSchema []byte
+
+//go:embed schemas/cyclonedx-1.5.schema.json
+var cyclonedxJSONSchema []byte
+
+// validateSBOMJSON validates rendered against the embedded SPDX 2.3 /
+// CycloneDX 1.5 JSON schema for format. The embedded schemas are condensed
+// to the fields flyctl-sbom actually emits rather than the full published
+// documents, but they're real JSON Schema - type, required, enum and
+// pattern constraints are all checked, not just key presence.
+func validateSBOMJSON(format string, rendered []byte) error {
+	var doc any
+	if err := json.Unmarshal(rendered, &doc); err != nil {
+		return fmt.Errorf("invalid %s output: %w", format, err)
+	}
+
+	var schemaJSON []byte
+	switch format {
+	case "spdx-json":
+		schemaJSON = spdxJSONSchema
+	case "cyclonedx-json":
+		schemaJSON = cyclonedxJSONSchema
+	default:
+		return fmt.Errorf("no validator registered for format %q", format)
+	}
+
+	var schema jsonSchemaNode
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return fmt.Errorf("invalid %s schema: %w", format, err)
+	}
+
+	if err := validateJSONSchema(schema, doc, ""); err != nil {
+		return fmt.Errorf("invalid %s output: %w", format, err)
+	}
+
+	return nil
+}
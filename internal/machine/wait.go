@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/jpillora/backoff"
@@ -70,6 +69,17 @@ func WaitForStartOrStop(ctx context.Context, machine *fly.Machine, action string
 	}
 }
 
+// waitResult is what each per-state goroutine in WaitForAnyMachineState
+// reports back, exactly once, over its result channel.
+type waitResult struct {
+	state string
+	err   error
+}
+
+// errMachineReachedState cancels the sibling goroutines in
+// WaitForAnyMachineState once one of them reports success.
+var errMachineReachedState = errors.New("machine reached a different awaited state")
+
 // returns when the machine is in one of the possible states, or after passing the timeout threshold
 func WaitForAnyMachineState(ctx context.Context, mach *fly.Machine, possibleStates []string, timeout time.Duration, sl statuslogger.StatusLine) (string, error) {
 	ctx, span := tracing.GetTracer().Start(ctx, "wait_for_machine_state", trace.WithAttributes(
@@ -80,59 +90,46 @@ func WaitForAnyMachineState(ctx context.Context, mach *fly.Machine, possibleStat
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	defer cancelCause(nil)
+
 	flapsClient := flapsutil.ClientFromContext(ctx)
 
-	var mutex sync.Mutex
-
-	var waitErr error
-	numCompleted := 0
-	var successfulState string
+	results := make(chan waitResult, len(possibleStates))
 
 	for _, state := range possibleStates {
 		state := state
 		go func() {
 			err := flapsClient.Wait(ctx, mach, state, timeout)
-			mutex.Lock()
-			defer func() {
-				numCompleted += 1
-				mutex.Unlock()
-			}()
-
-			if successfulState != "" {
-				return
-			}
-
-			if sl != nil {
-				sl.LogStatus(statuslogger.StatusRunning, fmt.Sprintf("Machine %s reached %s state", mach.ID, state))
-			}
-
-			if err != nil {
-				waitErr = err
-			} else {
-				successfulState = state
-			}
+			results <- waitResult{state: state, err: err}
 		}()
 	}
 
-	// TODO(billy): i'm sure we can use channels here
-	for {
-		mutex.Lock()
-		if successfulState != "" || numCompleted == len(possibleStates) {
-			defer mutex.Unlock()
-			if successfulState != "" {
-				span.SetAttributes(attribute.String("state", successfulState))
+	var waitErr error
+	for completed := 0; completed < len(possibleStates); completed++ {
+		select {
+		case res := <-results:
+			if sl != nil {
+				sl.LogStatus(statuslogger.StatusRunning, fmt.Sprintf("Machine %s reached %s state", mach.ID, res.state))
 			}
 
-			if waitErr != nil {
-				span.RecordError(waitErr)
+			if res.err != nil {
+				waitErr = res.err
+				continue
 			}
 
-			return successfulState, waitErr
+			span.SetAttributes(attribute.String("state", res.state))
+			cancelCause(errMachineReachedState)
+			return res.state, nil
+		case <-ctx.Done():
+			waitErr = context.Cause(ctx)
+			span.RecordError(waitErr)
+			return "", waitErr
 		}
-		mutex.Unlock()
-
-		time.Sleep(1 * time.Second)
 	}
+
+	span.RecordError(waitErr)
+	return "", waitErr
 }
 
 type WaitTimeoutErr struct {
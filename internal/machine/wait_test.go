@@ -0,0 +1,82 @@
+package machine
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	fly "github.com/superfly/fly-go"
+	"github.com/superfly/flyctl/internal/flapsutil"
+)
+
+// fakeWaitFlapsClient only implements Wait; every other method panics if
+// called, which is fine since WaitForAnyMachineState never touches them.
+type fakeWaitFlapsClient struct {
+	flapsutil.FlapsClient
+	wait func(ctx context.Context, mach *fly.Machine, state string, timeout time.Duration) error
+}
+
+func (f *fakeWaitFlapsClient) Wait(ctx context.Context, mach *fly.Machine, state string, timeout time.Duration) error {
+	return f.wait(ctx, mach, state, timeout)
+}
+
+// TestWaitForAnyMachineStateNoGoroutineLeak runs many concurrent calls, each
+// racing several per-state goroutines, and asserts the function returns
+// promptly on first success without leaking any goroutine. Run with -race to
+// catch any remaining shared-state access.
+func TestWaitForAnyMachineStateNoGoroutineLeak(t *testing.T) {
+	mach := &fly.Machine{ID: "1234567890abcd"}
+
+	client := &fakeWaitFlapsClient{
+		wait: func(ctx context.Context, mach *fly.Machine, state string, timeout time.Duration) error {
+			if state == "started" {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	ctx := flapsutil.NewContextWithClient(context.Background(), client)
+
+	before := runtime.NumGoroutine()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			state, err := WaitForAnyMachineState(ctx, mach, []string{"started", "stopped", "destroyed"}, 5*time.Second, nil)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if state != "started" {
+				t.Errorf("expected state %q, got %q", "started", state)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("WaitForAnyMachineState calls did not return in time; suspect a leaked goroutine or deadlock")
+	}
+
+	// Every per-state goroutine, including the siblings cancelled by the
+	// first success, must exit shortly after its call returns.
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running after all calls returned, want at most %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}